@@ -0,0 +1,36 @@
+package api
+
+// NeighborEventType classifies a NeighborEvent.
+type NeighborEventType string
+
+// Supported neighbor event types.
+const (
+	NeighborEventStateChanged NeighborEventType = "state_changed"
+)
+
+// NeighborEvent is emitted whenever a peer's session state changes,
+// e.g. established -> down after a flap.
+type NeighborEvent struct {
+	RouteServerID string            `json:"route_server_id"`
+	NeighborID    string            `json:"neighbor_id"`
+	Type          NeighborEventType `json:"type"`
+	State         string            `json:"state"`
+}
+
+// RouteEventType classifies a RouteEvent.
+type RouteEventType string
+
+// Supported route event types.
+const (
+	RouteEventAdded    RouteEventType = "added"
+	RouteEventWithdraw RouteEventType = "withdrawn"
+)
+
+// RouteEvent is emitted whenever a route is added to or withdrawn
+// from a neighbor's table.
+type RouteEvent struct {
+	RouteServerID string         `json:"route_server_id"`
+	NeighborID    string         `json:"neighbor_id"`
+	Type          RouteEventType `json:"type"`
+	Route         *Route         `json:"route,omitempty"`
+}
@@ -0,0 +1,28 @@
+package api
+
+import "time"
+
+// Neighbor describes a single BGP session of a route server, as
+// shown on a route server's neighbors list.
+type Neighbor struct {
+	ID            string
+	RouteServerID string
+	Address       string
+	ASN           int
+	State         string
+	Description   string
+
+	RoutesReceived int
+	RoutesAccepted int
+	RoutesFiltered int
+	RoutesExported int
+
+	// RoutesByFamily holds the same counters as the Routes* fields
+	// above, split out per AFI/SAFI family, for sources supporting
+	// multiple families (GoBGP fills it from every family a peer
+	// reports, whether or not Config.Families is set). Keyed by
+	// family name, e.g. "ipv4-unicast" or "l3vpn-ipv4-unicast".
+	RoutesByFamily map[string]NeighborRouteStats
+
+	Uptime time.Duration
+}
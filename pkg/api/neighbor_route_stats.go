@@ -0,0 +1,15 @@
+package api
+
+// NeighborRouteStats holds the route counters for a single AFI/SAFI
+// family of a neighbor, e.g. "ipv4-unicast" or "l3vpn-ipv4-unicast".
+// Sources supporting multiple families (GoBGP, whether or not
+// Config.Families is set) populate Neighbor.RoutesByFamily with one
+// entry per known family a peer reports, in
+// addition to the aggregated Routes* fields kept for backwards
+// compatibility with UIs that do not split by family.
+type NeighborRouteStats struct {
+	Received int
+	Accepted int
+	Filtered int
+	Exported int
+}
@@ -0,0 +1,49 @@
+package api
+
+import "fmt"
+
+// Route is a single path to a prefix, as returned by a route server's
+// RIB. It is intentionally sparse: only the attributes RouteFilter
+// needs to apply its ASN / large-community / ext-community criteria
+// are declared here.
+type Route struct {
+	// Family is the named AFI/SAFI table this route was fetched
+	// under, e.g. "ipv4-unicast", matching RouteFilter.Family.
+	Family string
+
+	// Bgp holds the route's BGP path attributes. Nil if the
+	// backend did not supply any (e.g. a static route).
+	Bgp *BgpInfo
+}
+
+// Routes is a list of routes, as returned in a RoutesResponse's
+// Imported / Filtered / NotExported fields.
+type Routes []*Route
+
+// BgpInfo holds the BGP path attributes of a Route relevant to
+// filtering and display.
+type BgpInfo struct {
+	// ASPath is the AS path as a sequence of ASNs, in the order
+	// they were traversed.
+	ASPath []int
+
+	// LargeCommunities are the route's RFC 8092 large communities.
+	LargeCommunities []LargeCommunity
+
+	// ExtCommunities are the route's extended communities, already
+	// formatted as "type:value1:value2" strings.
+	ExtCommunities []string
+}
+
+// LargeCommunity is a single RFC 8092 large BGP community.
+type LargeCommunity struct {
+	ASN   int
+	Data1 int
+	Data2 int
+}
+
+// String formats the large community as "asn:data1:data2", the form
+// used by RouteFilter.LargeCommunity.
+func (c LargeCommunity) String() string {
+	return fmt.Sprintf("%d:%d:%d", c.ASN, c.Data1, c.Data2)
+}
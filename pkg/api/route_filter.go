@@ -0,0 +1,145 @@
+package api
+
+// MatchMode describes how a prefix filter should be
+// applied relative to the supplied prefix.
+type MatchMode string
+
+// Supported match modes for prefix lookups.
+const (
+	MatchExact   MatchMode = "exact"
+	MatchLonger  MatchMode = "longer"
+	MatchShorter MatchMode = "shorter"
+)
+
+// RouteFilter is a shared, source-agnostic description of a
+// route / prefix query. It is deliberately sparse: every field
+// is optional and a nil RouteFilter (or a zero value) means
+// "no filtering", so existing callers can keep passing nil.
+type RouteFilter struct {
+	// Prefix restricts results to routes matching this prefix,
+	// interpreted according to Match.
+	Prefix string
+
+	// Match selects how Prefix is applied. Defaults to MatchExact
+	// when a Prefix is given and Match is empty.
+	Match MatchMode
+
+	// AFI restricts results to a single address family, e.g.
+	// "ipv4" or "ipv6". With AFI and Family both empty, bgplgd
+	// searches every family, while GoBGP queries only its default
+	// family (ipv4-unicast).
+	AFI string
+
+	// Family restricts results to a single named AFI/SAFI route
+	// table, e.g. "ipv4-unicast" or "l3vpn-ipv4-unicast", as
+	// exposed by the ?family= query parameter on routes endpoints.
+	// It takes precedence over AFI when both are set. Empty means
+	// "use the source's default family".
+	Family string
+
+	// ASN restricts results to routes with this ASN somewhere
+	// in the AS path. Zero means "no ASN filter".
+	ASN int
+
+	// LargeCommunity restricts results to routes carrying this
+	// large community, e.g. "64496:1:1".
+	LargeCommunity string
+
+	// ExtCommunity restricts results to routes carrying this
+	// extended community, e.g. "rt:64496:1".
+	ExtCommunity string
+}
+
+// IsEmpty returns true if the filter has no constraints set,
+// in which case sources should skip filtering entirely.
+func (f *RouteFilter) IsEmpty() bool {
+	if f == nil {
+		return true
+	}
+	return f.Prefix == "" &&
+		f.AFI == "" &&
+		f.Family == "" &&
+		f.ASN == 0 &&
+		f.LargeCommunity == "" &&
+		f.ExtCommunity == ""
+}
+
+// MatchesRoute applies the criteria a backend cannot already express
+// in its own query (ASN-in-path, large/ext community) to a single
+// route. Prefix/Match/AFI/Family are expected to have been applied by
+// the caller when building the upstream request, so they are not
+// re-checked here. A nil or empty filter always matches.
+func (f *RouteFilter) MatchesRoute(route *Route) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if f.ASN != 0 && !routeASPathContains(route, f.ASN) {
+		return false
+	}
+	if f.LargeCommunity != "" && !routeHasLargeCommunity(route, f.LargeCommunity) {
+		return false
+	}
+	if f.ExtCommunity != "" && !routeHasExtCommunity(route, f.ExtCommunity) {
+		return false
+	}
+	return true
+}
+
+// Matches reports whether routes contains at least one route
+// satisfying every MatchesRoute criterion. This is for backends like
+// GoBGP's ListPath, which return routes grouped by destination: the
+// whole group is kept if any of its paths match. An empty filter or
+// empty routes always matches.
+func (f *RouteFilter) Matches(routes Routes) bool {
+	if f.IsEmpty() || len(routes) == 0 {
+		return true
+	}
+	for _, route := range routes {
+		if f.MatchesRoute(route) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeASPathContains checks if asn appears anywhere in the route's
+// AS path.
+func routeASPathContains(route *Route, asn int) bool {
+	if route.Bgp == nil {
+		return false
+	}
+	for _, hop := range route.Bgp.ASPath {
+		if hop == asn {
+			return true
+		}
+	}
+	return false
+}
+
+// routeHasLargeCommunity checks if the route carries the large
+// community given as a "asn:value1:value2" string.
+func routeHasLargeCommunity(route *Route, community string) bool {
+	if route.Bgp == nil {
+		return false
+	}
+	for _, c := range route.Bgp.LargeCommunities {
+		if c.String() == community {
+			return true
+		}
+	}
+	return false
+}
+
+// routeHasExtCommunity checks if the route carries the extended
+// community given as a "type:value1:value2" string.
+func routeHasExtCommunity(route *Route, community string) bool {
+	if route.Bgp == nil {
+		return false
+	}
+	for _, c := range route.Bgp.ExtCommunities {
+		if c == community {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package api
+
+import "testing"
+
+func TestRouteFilterMatchesRouteASN(t *testing.T) {
+	route := &Route{Bgp: &BgpInfo{ASPath: []int{64496, 64497}}}
+
+	f := &RouteFilter{ASN: 64497}
+	if !f.MatchesRoute(route) {
+		t.Errorf("expected route with ASN 64497 in path to match")
+	}
+
+	f = &RouteFilter{ASN: 64498}
+	if f.MatchesRoute(route) {
+		t.Errorf("expected route without ASN 64498 in path not to match")
+	}
+}
+
+func TestRouteFilterMatchesRouteLargeCommunity(t *testing.T) {
+	route := &Route{Bgp: &BgpInfo{
+		LargeCommunities: []LargeCommunity{{ASN: 64496, Data1: 1, Data2: 1}},
+	}}
+
+	f := &RouteFilter{LargeCommunity: "64496:1:1"}
+	if !f.MatchesRoute(route) {
+		t.Errorf("expected route carrying 64496:1:1 to match")
+	}
+
+	f = &RouteFilter{LargeCommunity: "64496:1:2"}
+	if f.MatchesRoute(route) {
+		t.Errorf("expected route not carrying 64496:1:2 not to match")
+	}
+}
+
+func TestRouteFilterMatchesRouteExtCommunity(t *testing.T) {
+	route := &Route{Bgp: &BgpInfo{ExtCommunities: []string{"rt:64496:1"}}}
+
+	f := &RouteFilter{ExtCommunity: "rt:64496:1"}
+	if !f.MatchesRoute(route) {
+		t.Errorf("expected route carrying rt:64496:1 to match")
+	}
+
+	f = &RouteFilter{ExtCommunity: "rt:64496:2"}
+	if f.MatchesRoute(route) {
+		t.Errorf("expected route not carrying rt:64496:2 not to match")
+	}
+}
+
+func TestRouteFilterMatchesRouteNilBgp(t *testing.T) {
+	route := &Route{}
+	f := &RouteFilter{ASN: 64496}
+	if f.MatchesRoute(route) {
+		t.Errorf("expected route with nil Bgp not to match an ASN filter")
+	}
+}
+
+func TestRouteFilterMatchesRouteEmptyFilter(t *testing.T) {
+	route := &Route{}
+	var f *RouteFilter
+	if !f.MatchesRoute(route) {
+		t.Errorf("expected a nil filter to match any route")
+	}
+}
+
+func TestRouteFilterMatches(t *testing.T) {
+	routes := Routes{
+		{Bgp: &BgpInfo{ASPath: []int{64496}}},
+		{Bgp: &BgpInfo{ASPath: []int{64497}}},
+	}
+
+	f := &RouteFilter{ASN: 64497}
+	if !f.Matches(routes) {
+		t.Errorf("expected at least one route with ASN 64497 to match")
+	}
+
+	f = &RouteFilter{ASN: 64498}
+	if f.Matches(routes) {
+		t.Errorf("expected no route with ASN 64498 not to match")
+	}
+
+	f = &RouteFilter{ASN: 64497}
+	if !f.Matches(nil) {
+		t.Errorf("expected an empty route set to always match")
+	}
+}
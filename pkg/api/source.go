@@ -0,0 +1,57 @@
+package api
+
+import "context"
+
+// Source is implemented by every route server backend (GoBGP,
+// bgplgd, ...) Alice can query. Callers (the store refresh loop, the
+// HTTP handlers serving the JSON API) talk to route servers
+// exclusively through this interface, so a new backend only needs to
+// implement it to be usable everywhere a Source is.
+type Source interface {
+	// Status returns whether the backend is reachable.
+	Status() (*StatusResponse, error)
+
+	// Neighbors returns the full neighbor list, including derived
+	// stats such as RoutesFiltered.
+	Neighbors(ctx context.Context) (*NeighborsResponse, error)
+
+	// NeighborsSummary returns the neighbor list without the more
+	// expensive derived stats, for fast overview views.
+	NeighborsSummary(ctx context.Context) (*NeighborsResponse, error)
+
+	// NeighborsStatus returns just the session state of every
+	// neighbor.
+	NeighborsStatus(ctx context.Context) (*NeighborsStatusResponse, error)
+
+	// Routes returns a neighbor's routes (received, filtered),
+	// optionally narrowed down by filter.
+	Routes(ctx context.Context, neighborID string, filter *RouteFilter) (*RoutesResponse, error)
+
+	// RoutesReceived returns the routes accepted from a neighbor,
+	// optionally narrowed down by filter.
+	RoutesReceived(ctx context.Context, neighborID string, filter *RouteFilter) (*RoutesResponse, error)
+
+	// RoutesFiltered returns the routes rejected from a neighbor,
+	// optionally narrowed down by filter.
+	RoutesFiltered(ctx context.Context, neighborID string, filter *RouteFilter) (*RoutesResponse, error)
+
+	// RoutesNotExported returns the routes withheld from export to
+	// a neighbor.
+	RoutesNotExported(ctx context.Context, neighborID string) (*RoutesResponse, error)
+
+	// AllRoutes returns every route known to the route server,
+	// across all neighbors, used to build the global search index.
+	AllRoutes(ctx context.Context) (*RoutesResponse, error)
+
+	// LookupPrefix searches for a prefix across all neighbors,
+	// optionally narrowed down further by filter.
+	LookupPrefix(ctx context.Context, prefix string, filter *RouteFilter) (*RoutesLookupResponse, error)
+
+	// Subscribe returns a pair of channels delivering neighbor and
+	// route events as they are observed, for the events SSE stream.
+	Subscribe(ctx context.Context) (<-chan NeighborEvent, <-chan RouteEvent, error)
+
+	// ExpireCaches evicts stale cache entries and returns how many
+	// were removed.
+	ExpireCaches() int
+}
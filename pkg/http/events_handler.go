@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	api "github.com/alice-lg/alice-lg/pkg/api"
+)
+
+// routeServerEvents mirrors the Subscribe method of api.Source, so
+// this handler can be used with any source implementation (GoBGP,
+// BgplgdSource, ...) without importing the sources packages here.
+type routeServerEvents interface {
+	Subscribe(ctx context.Context) (<-chan api.NeighborEvent, <-chan api.RouteEvent, error)
+}
+
+// EventsHandler streams neighbor and route events for a single
+// route server as server-sent events, so the Alice UI can react to
+// peer flaps and route changes without polling.
+//
+// Route: GET /api/v1/routeservers/:id/events
+func EventsHandler(src routeServerEvents) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		neighbors, routes, err := src.Subscribe(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev, ok := <-neighbors:
+				if !ok {
+					return
+				}
+				writeSSE(w, "neighbor", ev)
+				flusher.Flush()
+			case ev, ok := <-routes:
+				if !ok {
+					return
+				}
+				writeSSE(w, "route", ev)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// writeSSE encodes payload as JSON and writes it as a single
+// server-sent event of the given event type.
+func writeSSE(w http.ResponseWriter, event string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, body)
+}
@@ -0,0 +1,16 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler exposes the process' Prometheus metrics, including
+// the per-source RPC/HTTP latency, cache and error counters
+// registered by pkg/sources/metrics.
+//
+// Route: GET /metrics
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	api "github.com/alice-lg/alice-lg/pkg/api"
+)
+
+// SourceRegistry looks up a route server's Source by its configured
+// ID, as used by the routes in this package to resolve the :id path
+// segment of an incoming request.
+type SourceRegistry interface {
+	Get(id string) (api.Source, bool)
+}
+
+// NewRouter builds the HTTP mux serving the endpoints in this
+// package on top of Alice's existing JSON API.
+//
+// Routes:
+//
+//	GET /api/v1/routeservers/:id/events
+//	GET /api/v1/routeservers/:id/neighbors/:neighborId/routes
+//	GET /metrics
+func NewRouter(sources SourceRegistry) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/routeservers/", routeServerRoute(sources))
+	mux.Handle("/metrics", MetricsHandler())
+	return mux
+}
+
+// routeServerRoute dispatches a request under /api/v1/routeservers/
+// to the events stream or the neighbor routes endpoint, depending on
+// which suffix the path matches.
+func routeServerRoute(sources SourceRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := routeServerIDFromEventsPath(r.URL.Path); ok {
+			src, ok := sources.Get(id)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			EventsHandler(src)(w, r)
+			return
+		}
+		if id, neighborID, ok := routeServerIDFromRoutesPath(r.URL.Path); ok {
+			src, ok := sources.Get(id)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			RoutesHandler(src, neighborID)(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// routeServerIDFromEventsPath extracts :id from a request path of
+// the form /api/v1/routeservers/:id/events.
+func routeServerIDFromEventsPath(path string) (string, bool) {
+	const prefix = "/api/v1/routeservers/"
+	const suffix = "/events"
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) {
+		return "", false
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(path, prefix), suffix)
+	if id == "" {
+		return "", false
+	}
+	return id, true
+}
+
+// routeServerIDFromRoutesPath extracts :id and :neighborId from a
+// request path of the form
+// /api/v1/routeservers/:id/neighbors/:neighborId/routes.
+func routeServerIDFromRoutesPath(path string) (id string, neighborID string, ok bool) {
+	const prefix = "/api/v1/routeservers/"
+	const suffix = "/routes"
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) {
+		return "", "", false
+	}
+	middle := strings.TrimSuffix(strings.TrimPrefix(path, prefix), suffix)
+	parts := strings.Split(middle, "/neighbors/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
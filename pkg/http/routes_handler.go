@@ -0,0 +1,44 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	api "github.com/alice-lg/alice-lg/pkg/api"
+)
+
+// routeServerRoutes mirrors the Routes method of api.Source, so this
+// handler can be used with any source implementation without
+// importing the sources packages here.
+type routeServerRoutes interface {
+	Routes(ctx context.Context, neighborID string, filter *api.RouteFilter) (*api.RoutesResponse, error)
+}
+
+// RoutesHandler serves a neighbor's routes, narrowed down by the
+// ?family= query parameter (e.g. "ipv6-unicast"), so the UI can
+// switch between a route server's configured AFI/SAFI families
+// without fetching and filtering the full route set client-side.
+//
+// Route: GET /api/v1/routeservers/:id/neighbors/:neighborId/routes
+func RoutesHandler(src routeServerRoutes, neighborID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := &api.RouteFilter{Family: r.URL.Query().Get("family")}
+		response, err := src.Routes(r.Context(), neighborID, filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, response)
+	}
+}
+
+// writeJSON encodes payload as JSON and writes it as the response
+// body, as every endpoint in this package returning an api.Response
+// does.
+func writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"net/http"
+)
+
+// ListenAndServe mounts NewRouter's routes and serves them on addr.
+// Serving NewRouter this way is what makes EventsHandler reachable;
+// nothing in this package calls ListenAndServe itself.
+func ListenAndServe(addr string, sources SourceRegistry) error {
+	return http.ListenAndServe(addr, NewRouter(sources))
+}
+
+// ListenAndServeMetrics serves only MetricsHandler on addr. Operators
+// can point this at an internal-only address to keep /metrics off the
+// same listener as the public API and events stream, rather than
+// relying on NewRouter's bundled /metrics route.
+func ListenAndServeMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", MetricsHandler())
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,33 @@
+package gobgp
+
+// Config holds the GoBGP source configuration as read from the
+// alice config file, e.g. "[source.neighbors_store.gobgp]" in the
+// alice.conf of a route server using a GoBGP backend.
+type Config struct {
+	// ID is the route server ID this source is associated with,
+	// used to tag cached responses and per-source metrics.
+	ID string
+
+	// Host is the gobgpd gRPC endpoint, e.g. "rs1.example.com:50051".
+	Host string
+
+	// Insecure disables TLS for the gRPC connection. Intended for
+	// gobgpd instances reachable only over a trusted network.
+	Insecure bool
+
+	// TLSCert and TLSCommonName configure the client TLS
+	// credentials used when Insecure is false.
+	TLSCert       string
+	TLSCommonName string
+
+	// ProcessingTimeout bounds, in seconds, how long a single gRPC
+	// call issued by this source is allowed to take before it is
+	// cancelled.
+	ProcessingTimeout int
+
+	// Families restricts AllRoutes to the named AFI/SAFI tables
+	// (e.g. "ipv4-unicast", "l3vpn-ipv6-unicast") instead of the
+	// default single-family dump. Empty means "use the default
+	// family only", preserving the pre-multi-family behavior.
+	Families []string
+}
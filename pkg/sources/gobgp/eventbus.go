@@ -0,0 +1,81 @@
+package gobgp
+
+import (
+	"sync"
+
+	api "github.com/alice-lg/alice-lg/pkg/api"
+)
+
+// eventBusQueueSize is the per-subscriber buffer depth. While a
+// subscriber is this far behind, new events are dropped for it (it
+// stays subscribed), so it cannot block event delivery to everyone
+// else.
+const eventBusQueueSize = 64
+
+// eventBus is a tiny fan-out mechanism: a single internal producer
+// publishes events, which are copied out to every currently
+// subscribed channel. Events that do not fit in a slow subscriber's
+// buffer are dropped for that subscriber rather than allowed to
+// block the producer.
+type eventBus struct {
+	mu           sync.Mutex
+	neighborSubs map[chan api.NeighborEvent]struct{}
+	routeSubs    map[chan api.RouteEvent]struct{}
+}
+
+// newEventBus creates an empty eventBus.
+func newEventBus() *eventBus {
+	return &eventBus{
+		neighborSubs: make(map[chan api.NeighborEvent]struct{}),
+		routeSubs:    make(map[chan api.RouteEvent]struct{}),
+	}
+}
+
+// subscribe registers a new pair of subscriber channels. The caller
+// is expected to drain both channels until ctx is done and then
+// call the returned unsubscribe function.
+func (b *eventBus) subscribe() (chan api.NeighborEvent, chan api.RouteEvent, func()) {
+	neighbors := make(chan api.NeighborEvent, eventBusQueueSize)
+	routes := make(chan api.RouteEvent, eventBusQueueSize)
+
+	b.mu.Lock()
+	b.neighborSubs[neighbors] = struct{}{}
+	b.routeSubs[routes] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		delete(b.neighborSubs, neighbors)
+		delete(b.routeSubs, routes)
+		b.mu.Unlock()
+		close(neighbors)
+		close(routes)
+	}
+	return neighbors, routes, unsubscribe
+}
+
+// publishNeighborEvent fans out ev to all subscribers, dropping it
+// for subscribers whose buffer is full.
+func (b *eventBus) publishNeighborEvent(ev api.NeighborEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.neighborSubs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// publishRouteEvent fans out ev to all subscribers, dropping it for
+// subscribers whose buffer is full.
+func (b *eventBus) publishRouteEvent(ev api.RouteEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.routeSubs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
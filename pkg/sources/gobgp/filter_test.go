@@ -0,0 +1,47 @@
+package gobgp
+
+import (
+	"testing"
+
+	gobgpapi "github.com/osrg/gobgp/api"
+)
+
+// TestRouteFamilyRoundTrip checks that every name in namedFamilies
+// survives a routeFamilyFromName -> routeFamilyNameFromFamily round
+// trip, since RoutesByFamily keys and Config.Families entries depend
+// on this mapping being stable in both directions.
+func TestRouteFamilyRoundTrip(t *testing.T) {
+	for name := range namedFamilies {
+		family := routeFamilyFromName(name)
+		got := routeFamilyNameFromFamily(family)
+		if got != name {
+			t.Errorf("routeFamilyNameFromFamily(routeFamilyFromName(%q)) = %q, want %q", name, got, name)
+		}
+	}
+}
+
+// TestRouteFamilyFromNameFallback checks that an unknown or empty
+// family name falls back to defaultFamilyName, preserving the
+// single-family behavior sources had before Config.Families existed.
+func TestRouteFamilyFromNameFallback(t *testing.T) {
+	want := namedFamilies[defaultFamilyName]
+	for _, name := range []string{"", "bogus-family"} {
+		got := routeFamilyFromName(name)
+		if got.Afi != want.Afi || got.Safi != want.Safi {
+			t.Errorf("routeFamilyFromName(%q) = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+// TestRouteFamilyNameFromFamilyUnknown checks that a family with no
+// entry in namedFamilies yields "", rather than misattributing routes
+// to the wrong RoutesByFamily key.
+func TestRouteFamilyNameFromFamilyUnknown(t *testing.T) {
+	unknown := &gobgpapi.Family{
+		Afi:  gobgpapi.Family_AFI_L2VPN,
+		Safi: gobgpapi.Family_SAFI_EVPN,
+	}
+	if got := routeFamilyNameFromFamily(unknown); got != "" {
+		t.Errorf("routeFamilyNameFromFamily(unknown) = %q, want \"\"", got)
+	}
+}
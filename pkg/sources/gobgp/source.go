@@ -7,11 +7,12 @@ import (
 
 	api "github.com/alice-lg/alice-lg/pkg/api"
 	"github.com/alice-lg/alice-lg/pkg/caches"
+	"github.com/alice-lg/alice-lg/pkg/sources/metrics"
 
 	"context"
-	"fmt"
 	"io"
 	"log"
+	"sync"
 	"time"
 )
 
@@ -28,6 +29,12 @@ type GoBGP struct {
 	routesReceivedCache    *caches.RoutesCache
 	routesFilteredCache    *caches.RoutesCache
 	routesNotExportedCache *caches.RoutesCache
+
+	// events fans out peer and route updates received via
+	// WatchEvent to Subscribe()rs. watchOnce makes sure the
+	// background stream is only started once, on first use.
+	events    *eventBus
+	watchOnce sync.Once
 }
 
 // NewGoBGP creates a new GoBGP source instance
@@ -81,6 +88,8 @@ func NewGoBGP(config Config) *GoBGP {
 		routesReceivedCache:    routesReceivedCache,
 		routesFilteredCache:    routesFilteredCache,
 		routesNotExportedCache: routesNotExportedCache,
+
+		events: newEventBus(),
 	}
 }
 
@@ -93,17 +102,20 @@ func (gobgp *GoBGP) ExpireCaches() int {
 
 // NeighborsStatus retrievs all status information
 // for all peers on the RS.
-func (gobgp *GoBGP) NeighborsStatus() (*api.NeighborsStatusResponse, error) {
+func (gobgp *GoBGP) NeighborsStatus(ctx context.Context) (*api.NeighborsStatusResponse, error) {
 	ctx, cancel := context.WithTimeout(
-		context.Background(),
+		ctx,
 		time.Second*time.Duration(gobgp.config.ProcessingTimeout))
 	defer cancel()
 
 	response := api.NeighborsStatusResponse{}
 	response.Neighbors = make(api.NeighborsStatus, 0)
 
+	done := metrics.ObserveRPC(gobgp.config.ID, "ListPeer")
 	resp, err := gobgp.client.ListPeer(ctx, &gobgpapi.ListPeerRequest{})
+	done()
 	if err != nil {
+		metrics.ObserveError(gobgp.config.ID, "ListPeer", err)
 		return nil, err
 	}
 	for {
@@ -137,8 +149,11 @@ func (gobgp *GoBGP) Status() (*api.StatusResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(gobgp.config.ProcessingTimeout))
 	defer cancel()
 
+	done := metrics.ObserveRPC(gobgp.config.ID, "GetBgp")
 	resp, err := gobgp.client.GetBgp(ctx, &gobgpapi.GetBgpRequest{})
+	done()
 	if err != nil {
+		metrics.ObserveError(gobgp.config.ID, "GetBgp", err)
 		return nil, err
 	}
 
@@ -149,17 +164,20 @@ func (gobgp *GoBGP) Status() (*api.StatusResponse, error) {
 }
 
 // Neighbors retrievs a list of neighbors
-func (gobgp *GoBGP) Neighbors() (*api.NeighborsResponse, error) {
+func (gobgp *GoBGP) Neighbors(ctx context.Context) (*api.NeighborsResponse, error) {
 	ctx, cancel := context.WithTimeout(
-		context.Background(),
+		ctx,
 		time.Second*time.Duration(gobgp.config.ProcessingTimeout))
 	defer cancel()
 
 	response := api.NeighborsResponse{}
 	response.Neighbors = make(api.Neighbors, 0)
 
+	done := metrics.ObserveRPC(gobgp.config.ID, "ListPeer")
 	resp, err := gobgp.client.ListPeer(ctx, &gobgpapi.ListPeerRequest{EnableAdvertised: true})
+	done()
 	if err != nil {
+		metrics.ObserveError(gobgp.config.ID, "ListPeer", err)
 		return nil, err
 	}
 	for {
@@ -184,11 +202,26 @@ func (gobgp *GoBGP) Neighbors() (*api.NeighborsResponse, error) {
 		neigh.RouteServerID = gobgp.config.ID
 
 		response.Neighbors = append(response.Neighbors, &neigh)
+		neigh.RoutesByFamily = make(map[string]api.NeighborRouteStats, len(_resp.Peer.AfiSafis))
 		for _, afiSafi := range _resp.Peer.AfiSafis {
-			neigh.RoutesReceived += int(afiSafi.State.Received)
-			neigh.RoutesExported += int(afiSafi.State.Advertised)
-			neigh.RoutesAccepted += int(afiSafi.State.Accepted)
-			neigh.RoutesFiltered += (neigh.RoutesReceived - neigh.RoutesAccepted)
+			received := int(afiSafi.State.Received)
+			accepted := int(afiSafi.State.Accepted)
+			stats := api.NeighborRouteStats{
+				Received: received,
+				Accepted: accepted,
+				Filtered: received - accepted,
+				Exported: int(afiSafi.State.Advertised),
+			}
+			if name := routeFamilyNameFromFamily(afiSafi.Config.Family); name != "" {
+				neigh.RoutesByFamily[name] = stats
+			}
+
+			// Keep the aggregated fields for callers that do not
+			// split by family.
+			neigh.RoutesReceived += stats.Received
+			neigh.RoutesExported += stats.Exported
+			neigh.RoutesAccepted += stats.Accepted
+			neigh.RoutesFiltered += stats.Filtered
 		}
 
 		if _resp.Peer.Timers.State.Uptime != nil {
@@ -203,33 +236,41 @@ func (gobgp *GoBGP) Neighbors() (*api.NeighborsResponse, error) {
 }
 
 // NeighborsSummary is an alias of Neighbors for now
-func (gobgp *GoBGP) NeighborsSummary() (*api.NeighborsResponse, error) {
-	return gobgp.Neighbors()
+func (gobgp *GoBGP) NeighborsSummary(ctx context.Context) (*api.NeighborsResponse, error) {
+	return gobgp.Neighbors(ctx)
 }
 
 // Routes retrieves filtered and exported routes
-func (gobgp *GoBGP) Routes(neighborID string) (*api.RoutesResponse, error) {
+func (gobgp *GoBGP) Routes(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
 	neigh, err := gobgp.lookupNeighbor(neighborID)
 	if err != nil {
 		return nil, err
 	}
 
 	routes := NewRoutesResponse()
-	err = gobgp.GetRoutes(neigh, gobgpapi.TableType_ADJ_IN, &routes)
+	err = gobgp.GetFilteredRoutes(ctx, neigh, gobgpapi.TableType_ADJ_IN, filter, &routes)
 	if err != nil {
 		return nil, err
 	}
 	return &routes, nil
 }
 
-func (gobgp *GoBGP) getRoutes(neighborID string) (*api.RoutesResponse, error) {
+func (gobgp *GoBGP) getRoutes(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
 	neigh, err := gobgp.lookupNeighbor(neighborID)
 	if err != nil {
 		return nil, err
 	}
 
 	routes := NewRoutesResponse()
-	err = gobgp.GetRoutes(neigh, gobgpapi.TableType_ADJ_IN, &routes)
+	err = gobgp.GetFilteredRoutes(ctx, neigh, gobgpapi.TableType_ADJ_IN, filter, &routes)
 	if err != nil {
 		return nil, err
 	}
@@ -246,19 +287,23 @@ func (gobgp *GoBGP) getRoutes(neighborID string) (*api.RoutesResponse, error) {
 // RoutesNotExported() API.
 //
 // A route deduplication is applied.
-func (gobgp *GoBGP) RoutesRequired(neighborID string) (*api.RoutesResponse, error) {
-	return gobgp.getRoutes(neighborID)
+func (gobgp *GoBGP) RoutesRequired(ctx context.Context, neighborID string) (*api.RoutesResponse, error) {
+	return gobgp.getRoutes(ctx, neighborID, nil)
 }
 
-// RoutesReceived gets all received routes
-func (gobgp *GoBGP) RoutesReceived(neighborID string) (*api.RoutesResponse, error) {
+// RoutesReceived gets all received routes matching filter
+func (gobgp *GoBGP) RoutesReceived(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
 	neigh, err := gobgp.lookupNeighbor(neighborID)
 	if err != nil {
 		return nil, err
 	}
 
 	routes := NewRoutesResponse()
-	err = gobgp.GetRoutes(neigh, gobgpapi.TableType_ADJ_IN, &routes)
+	err = gobgp.GetFilteredRoutes(ctx, neigh, gobgpapi.TableType_ADJ_IN, filter, &routes)
 	if err != nil {
 		return nil, err
 	}
@@ -266,9 +311,13 @@ func (gobgp *GoBGP) RoutesReceived(neighborID string) (*api.RoutesResponse, erro
 	return &routes, nil
 }
 
-// RoutesFiltered gets all filtered routes
-func (gobgp *GoBGP) RoutesFiltered(neighborID string) (*api.RoutesResponse, error) {
-	routes, err := gobgp.getRoutes(neighborID)
+// RoutesFiltered gets all filtered routes matching filter
+func (gobgp *GoBGP) RoutesFiltered(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
+	routes, err := gobgp.getRoutes(ctx, neighborID, filter)
 	if err != nil {
 		log.Print(err)
 	}
@@ -277,38 +326,105 @@ func (gobgp *GoBGP) RoutesFiltered(neighborID string) (*api.RoutesResponse, erro
 }
 
 // RoutesNotExported gets all not exported routes
-func (gobgp *GoBGP) RoutesNotExported(neighborID string) (*api.RoutesResponse, error) {
+func (gobgp *GoBGP) RoutesNotExported(ctx context.Context, neighborID string) (*api.RoutesResponse, error) {
 	neigh, err := gobgp.lookupNeighbor(neighborID)
 	if err != nil {
 		return nil, err
 	}
 	routes := NewRoutesResponse()
-	err = gobgp.GetRoutes(neigh, gobgpapi.TableType_ADJ_OUT, &routes)
+	err = gobgp.GetFilteredRoutes(ctx, neigh, gobgpapi.TableType_ADJ_OUT, nil, &routes)
 	if err != nil {
 		return nil, err
 	}
 	routes.NotExported = routes.Filtered
+	routes.Filtered = nil
 	return &routes, nil
 }
 
-// LookupPrefix searches for a prefix
-func (gobgp *GoBGP) LookupPrefix(prefix string) (*api.RoutesLookupResponse, error) {
-	return nil, fmt.Errorf("not implemented: LookupPrefix")
+// LookupPrefix searches for a prefix across all peers, optionally
+// narrowed down further by filter (e.g. address family or community).
+// It issues one ADJ_IN ListPath per peer via GetFilteredRoutes: gobgp
+// requires a peer Name for ADJ_IN/ADJ_OUT ListPath requests, so there
+// is no single cross-peer lookup to make, unlike the TableType_GLOBAL
+// table which is not scoped per peer. A single cfg.ProcessingTimeout
+// deadline is set once for the whole peer loop rather than left to
+// GetFilteredRoutes's own per-call timeout, so one slow peer can't
+// stretch the worst case to peers * ProcessingTimeout.
+func (gobgp *GoBGP) LookupPrefix(
+	ctx context.Context,
+	prefix string,
+	filter *api.RouteFilter,
+) (*api.RoutesLookupResponse, error) {
+	if filter == nil {
+		filter = &api.RouteFilter{}
+	}
+	filter.Prefix = prefix
+
+	ctx, cancel := context.WithTimeout(
+		ctx,
+		time.Second*time.Duration(gobgp.config.ProcessingTimeout))
+	defer cancel()
+
+	peers, err := gobgp.GetNeighbors()
+	if err != nil {
+		return nil, err
+	}
+
+	routes := NewRoutesResponse()
+	for _, peer := range peers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := gobgp.GetFilteredRoutes(
+			ctx, peer, gobgpapi.TableType_ADJ_IN, filter, &routes,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return &api.RoutesLookupResponse{Routes: routes.Imported}, nil
 }
 
 // AllRoutes returns a routes dump (filtered, received),
 // which is used to learn all prefixes to build
-// up a local store for searching.
-func (gobgp *GoBGP) AllRoutes() (*api.RoutesResponse, error) {
+// up a local store for searching. If Config.Families is set, one
+// ListPath is issued per configured family instead of relying on
+// the gobgp server's default table, so dual-stack and L3VPN route
+// servers are dumped in full rather than collapsing onto a single
+// family.
+func (gobgp *GoBGP) AllRoutes(ctx context.Context) (*api.RoutesResponse, error) {
 	routes := NewRoutesResponse()
 	peers, err := gobgp.GetNeighbors()
 	if err != nil {
 		return nil, err
 	}
+
+	families := gobgp.config.Families
+	if len(families) == 0 {
+		for _, peer := range peers {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if err := gobgp.GetFilteredRoutes(
+				ctx, peer, gobgpapi.TableType_ADJ_IN, nil, &routes,
+			); err != nil {
+				log.Print(err)
+			}
+		}
+		return &routes, nil
+	}
+
 	for _, peer := range peers {
-		err = gobgp.GetRoutes(peer, gobgpapi.TableType_ADJ_IN, &routes)
-		if err != nil {
-			log.Print(err)
+		for _, family := range families {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			filter := &api.RouteFilter{Family: family}
+			if err := gobgp.GetFilteredRoutes(
+				ctx, peer, gobgpapi.TableType_ADJ_IN, filter, &routes,
+			); err != nil {
+				log.Print(err)
+			}
 		}
 	}
 	return &routes, nil
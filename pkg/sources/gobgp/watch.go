@@ -0,0 +1,148 @@
+package gobgp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	gobgpapi "github.com/osrg/gobgp/api"
+
+	api "github.com/alice-lg/alice-lg/pkg/api"
+)
+
+// watchReconnectDelay is the minimum pause between retries of a
+// failed WatchEvent stream, so a gobgp that stays unreachable turns
+// this into a slow retry loop rather than a busy loop.
+const watchReconnectDelay = 5 * time.Second
+
+// Subscribe returns a pair of channels delivering peer and route
+// events as they are observed on the gobgp WatchEvent stream. The
+// underlying watch goroutine is started lazily on first subscribe
+// and kept running for the lifetime of the process; ctx only
+// controls this particular subscription.
+func (gobgp *GoBGP) Subscribe(
+	ctx context.Context,
+) (<-chan api.NeighborEvent, <-chan api.RouteEvent, error) {
+	gobgp.watchOnce.Do(func() {
+		go gobgp.watch()
+	})
+
+	neighbors, routes, unsubscribe := gobgp.events.subscribe()
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	return neighbors, routes, nil
+}
+
+// watch opens a WatchEvent stream subscribing to PEER and TABLE
+// updates and republishes them on the internal event bus. On
+// failure it logs and retries with a fresh stream, as this is
+// expected to run for the lifetime of the process.
+func (gobgp *GoBGP) watch() {
+	for {
+		if err := gobgp.watchStream(); err != nil {
+			log.Printf("gobgp watch: %s, retrying", err)
+			time.Sleep(watchReconnectDelay)
+		}
+	}
+}
+
+// watchStream opens a single WatchEvent stream and forwards
+// events until it ends (error or EOF).
+func (gobgp *GoBGP) watchStream() error {
+	ctx := context.Background()
+	stream, err := gobgp.client.WatchEvent(ctx, &gobgpapi.WatchEventRequest{
+		Peer:  &gobgpapi.WatchEventRequest_Peer{},
+		Table: &gobgpapi.WatchEventRequest_Table{},
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		gobgp.dispatchWatchEvent(ev)
+	}
+}
+
+// dispatchWatchEvent translates a single gobgp WatchEventResponse
+// into our internal event types and publishes them on the bus.
+func (gobgp *GoBGP) dispatchWatchEvent(ev *gobgpapi.WatchEventResponse) {
+	if peer := ev.GetPeer(); peer != nil && peer.Peer != nil {
+		state := "down"
+		if peer.Peer.State.SessionState == gobgpapi.PeerState_ESTABLISHED {
+			state = "up"
+		}
+		gobgp.events.publishNeighborEvent(api.NeighborEvent{
+			RouteServerID: gobgp.config.ID,
+			NeighborID:    PeerHash(peer.Peer),
+			Type:          api.NeighborEventStateChanged,
+			State:         state,
+		})
+		gobgp.neighborsCache.Expire()
+	}
+
+	if table := ev.GetTable(); table != nil {
+		for _, path := range table.Paths {
+			prefix, err := nlriPrefixString(path.Nlri)
+			if err != nil {
+				continue
+			}
+			route, err := decodePath(prefix, path)
+			if err != nil {
+				continue
+			}
+			evType := api.RouteEventAdded
+			if path.IsWithdraw {
+				evType = api.RouteEventWithdraw
+			}
+			gobgp.events.publishRouteEvent(api.RouteEvent{
+				RouteServerID: gobgp.config.ID,
+				NeighborID:    neighborIDFromPath(path),
+				Type:          evType,
+				Route:         route,
+			})
+		}
+		gobgp.routesReceivedCache.Expire()
+	}
+}
+
+// neighborIDFromPath computes the same neighbor ID PeerHash derives
+// from a full Peer, using only the NeighborIp a TABLE watch path
+// carries, so RouteEvents can be attributed to a peer the way
+// NeighborEvents already are.
+func neighborIDFromPath(path *gobgpapi.Path) string {
+	return PeerHash(&gobgpapi.Peer{
+		State: &gobgpapi.PeerState{NeighborAddress: path.NeighborIp},
+	})
+}
+
+// nlriPrefixString unmarshals a WatchEvent path's NLRI and renders it
+// as a "ip/prefixlen" prefix string. Unlike ListPath's Destination,
+// which carries a pre-decoded Prefix field, raw TABLE watch paths
+// only carry the NLRI as an opaque protobuf Any, so it has to be
+// unmarshalled into a concrete type by hand. IPAddressPrefix covers
+// plain ipv4/ipv6 unicast; LabeledVPNIPAddressPrefix covers the
+// l3vpn families.
+func nlriPrefixString(nlri *any.Any) (string, error) {
+	var ip gobgpapi.IPAddressPrefix
+	if err := ptypes.UnmarshalAny(nlri, &ip); err == nil {
+		return fmt.Sprintf("%s/%d", ip.Prefix, ip.PrefixLen), nil
+	}
+	var vpn gobgpapi.LabeledVPNIPAddressPrefix
+	if err := ptypes.UnmarshalAny(nlri, &vpn); err == nil {
+		return fmt.Sprintf("%s/%d", vpn.Prefix, vpn.PrefixLen), nil
+	}
+	return "", fmt.Errorf("unsupported NLRI type: %s", nlri.GetTypeUrl())
+}
@@ -0,0 +1,127 @@
+// Package metrics exposes Prometheus instrumentation shared by all
+// Alice sources, so that slow route servers (or slow neighbors
+// within one route server) can be spotted without reaching for
+// tcpdump.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rpcBuckets covers sub-millisecond to multi-second RPCs. The low
+// end is deliberately fine grained: a gRPC call answered from an
+// in-memory table can complete in well under a millisecond, and
+// the default Prometheus buckets would round that down to zero.
+var rpcBuckets = []float64{
+	.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10,
+}
+
+// RPCDuration tracks gRPC call latency, labelled by source and method
+// (e.g. "ListPeer", "GetBgp", "ListPath").
+var RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "alice_lg",
+	Subsystem: "source",
+	Name:      "rpc_duration_seconds",
+	Help:      "Duration of gRPC calls made by a source, by source and method.",
+	Buckets:   rpcBuckets,
+}, []string{"source_id", "method"})
+
+// HTTPDuration tracks outgoing HTTP request latency, labelled by
+// source and method (e.g. "neighbors", "rib").
+var HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "alice_lg",
+	Subsystem: "source",
+	Name:      "http_duration_seconds",
+	Help:      "Duration of HTTP requests made by a source, by source and method.",
+	Buckets:   rpcBuckets,
+}, []string{"source_id", "method"})
+
+// CacheResults counts cache hits and misses, labelled by source,
+// cache name (e.g. "neighbors", "routes_received") and result
+// ("hit" or "miss").
+var CacheResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "alice_lg",
+	Subsystem: "source",
+	Name:      "cache_results_total",
+	Help:      "Cache hits and misses, by source, cache and result.",
+}, []string{"source_id", "cache", "result"})
+
+// Errors counts errors returned by a source, labelled by source,
+// method and error class (e.g. "unavailable", "timeout", "decode").
+var Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "alice_lg",
+	Subsystem: "source",
+	Name:      "errors_total",
+	Help:      "Errors encountered by a source, by source, method and class.",
+}, []string{"source_id", "method", "class"})
+
+func init() {
+	prometheus.MustRegister(RPCDuration, HTTPDuration, CacheResults, Errors)
+}
+
+// ObserveRPC times a gRPC call and records it under RPCDuration. Use
+// as: defer metrics.ObserveRPC(sourceID, "ListPeer")()
+func ObserveRPC(sourceID, method string) func() {
+	start := time.Now()
+	return func() {
+		RPCDuration.WithLabelValues(sourceID, method).Observe(
+			time.Since(start).Seconds())
+	}
+}
+
+// ObserveHTTP times an outgoing HTTP request and records it under
+// HTTPDuration. Use as: defer metrics.ObserveHTTP(sourceID, "rib")()
+func ObserveHTTP(sourceID, method string) func() {
+	start := time.Now()
+	return func() {
+		HTTPDuration.WithLabelValues(sourceID, method).Observe(
+			time.Since(start).Seconds())
+	}
+}
+
+// CacheHit records a cache hit for cache on sourceID.
+func CacheHit(sourceID, cache string) {
+	CacheResults.WithLabelValues(sourceID, cache, "hit").Inc()
+}
+
+// CacheMiss records a cache miss for cache on sourceID.
+func CacheMiss(sourceID, cache string) {
+	CacheResults.WithLabelValues(sourceID, cache, "miss").Inc()
+}
+
+// ObserveError classifies err and records it under Errors for
+// sourceID/method. A nil err is a no-op, so callers can pass a
+// call's error result unconditionally or call this directly on an
+// error return path.
+func ObserveError(sourceID, method string, err error) {
+	if err == nil {
+		return
+	}
+	Errors.WithLabelValues(sourceID, method, classifyError(err)).Inc()
+}
+
+// classifyError maps an error to a coarse class used as a metric
+// label. Label values must stay low-cardinality, so this never
+// includes the error message itself.
+func classifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable:
+			return "unavailable"
+		case codes.DeadlineExceeded:
+			return "timeout"
+		case codes.Canceled:
+			return "canceled"
+		}
+	}
+	return "decode"
+}
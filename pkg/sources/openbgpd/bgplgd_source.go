@@ -1,15 +1,28 @@
 package openbgpd
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/alice-lg/alice-lg/pkg/api"
 	"github.com/alice-lg/alice-lg/pkg/caches"
 	"github.com/alice-lg/alice-lg/pkg/decoders"
+	"github.com/alice-lg/alice-lg/pkg/sources/metrics"
 )
 
+// allRoutesSingleflightKey is the singleflight key used to coalesce
+// concurrent AllRoutes calls, which take no per-call argument to
+// key on.
+const allRoutesSingleflightKey = "all"
+
 const (
 	// BgplgdSourceVersion is currently fixed at 1.0
 	BgplgdSourceVersion = "1.0"
@@ -31,6 +44,18 @@ type BgplgdSource struct {
 	routesCache         *caches.RoutesCache
 	routesReceivedCache *caches.RoutesCache
 	routesFilteredCache *caches.RoutesCache
+
+	// events emulates the push-based GoBGP Subscribe API by
+	// polling and diffing against lastNeighborsSnapshot. watchOnce
+	// ensures the poller is only started once.
+	events                *eventBus
+	watchOnce             sync.Once
+	lastNeighborsSnapshot api.NeighborsStatus
+
+	// ribRequests coalesces concurrent RIB fetches for the same
+	// neighbor (or, keyed by allRoutesSingleflightKey, for the
+	// entire table) into a single upstream request.
+	ribRequests singleflight.Group
 }
 
 // NewBgplgdSource creates a new source instance with a configuration.
@@ -51,6 +76,7 @@ func NewBgplgdSource(cfg *Config) *BgplgdSource {
 		routesCache:           rc,
 		routesReceivedCache:   rrc,
 		routesFilteredCache:   rfc,
+		events:                newEventBus(),
 	}
 }
 
@@ -87,6 +113,27 @@ func (src *BgplgdSource) ShowNeighborRIBRequest(
 	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 }
 
+// ShowNeighborRIBFilterRequest retrives the routes accepted from the
+// neighbor identified by bgp-id, narrowed down by filter. ASN and
+// community criteria cannot be expressed as bgplgd query parameters,
+// so they are applied afterwards by filteredRoutesResponse via
+// filter.MatchesRoute.
+func (src *BgplgdSource) ShowNeighborRIBFilterRequest(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*http.Request, error) {
+	url := src.cfg.APIURL("/rib?neighbor=%s", neighborID)
+	if filter.Prefix != "" {
+		url += fmt.Sprintf("&prefix=%s&match=%s",
+			filter.Prefix, bgplgdMatchParam(filter.Match))
+	}
+	if family := bgplgdFamilyParam(filter); family != "" {
+		url += fmt.Sprintf("&af=%s", family)
+	}
+	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+}
+
 // ShowRIBRequest makes a request for retrieving all routes imported
 // from all peers
 func (src *BgplgdSource) ShowRIBRequest(ctx context.Context) (*http.Request, error) {
@@ -94,6 +141,54 @@ func (src *BgplgdSource) ShowRIBRequest(ctx context.Context) (*http.Request, err
 	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 }
 
+// ShowRIBPrefixRequest builds a prefix lookup request against the
+// bgplgd /rib endpoint, translating filter into query parameters.
+// ASN and community criteria cannot be expressed as bgplgd query
+// parameters, so they are applied afterwards by LookupPrefix via
+// filter.MatchesRoute.
+func (src *BgplgdSource) ShowRIBPrefixRequest(
+	ctx context.Context,
+	filter *api.RouteFilter,
+) (*http.Request, error) {
+	url := src.cfg.APIURL("/rib?prefix=%s&match=%s",
+		filter.Prefix, bgplgdMatchParam(filter.Match))
+	if family := bgplgdFamilyParam(filter); family != "" {
+		url += fmt.Sprintf("&af=%s", family)
+	}
+	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+}
+
+// doRequest executes req, instrumenting its duration and any error
+// under metrics.HTTPDuration / metrics.Errors, labelled by method
+// (e.g. "neighbors", "rib"). A per-call deadline derived from
+// cfg.ProcessingTimeout is applied on top of req's existing context,
+// so a hung bgplgd does not block the caller forever even when the
+// inbound context carries no deadline of its own. Funnelling every
+// call through here keeps that timeout in one place instead of
+// repeating it at each request-building call site.
+func (src *BgplgdSource) doRequest(method string, req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(
+		req.Context(),
+		time.Second*time.Duration(src.cfg.ProcessingTimeout))
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	done := metrics.ObserveHTTP(src.cfg.ID, method)
+	res, err := http.DefaultClient.Do(req)
+	if err == nil {
+		// The body is read here, while ctx is still live: cancel()
+		// runs when doRequest returns, which would abort any later
+		// read of res.Body by the caller.
+		var body []byte
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	done()
+	metrics.ObserveError(src.cfg.ID, method, err)
+	return res, err
+}
+
 // Datasource
 // ==========
 
@@ -126,20 +221,22 @@ func (src *BgplgdSource) Status() (*api.StatusResponse, error) {
 }
 
 // Neighbors retrievs a full list of all neighbors
-func (src *BgplgdSource) Neighbors() (*api.NeighborsResponse, error) {
+func (src *BgplgdSource) Neighbors(ctx context.Context) (*api.NeighborsResponse, error) {
 	// Query cache and see if we have a hit
 	response := src.neighborsCache.Get()
 	if response != nil {
+		metrics.CacheHit(src.cfg.ID, "neighbors")
 		response.Meta.ResultFromCache = true
 		return response, nil
 	}
+	metrics.CacheMiss(src.cfg.ID, "neighbors")
 
 	// Make API request and read response
-	req, err := src.ShowNeighborsRequest(context.Background())
+	req, err := src.ShowNeighborsRequest(ctx)
 	if err != nil {
 		return nil, err
 	}
-	res, err := http.DefaultClient.Do(req)
+	res, err := src.doRequest("neighbors", req)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +253,7 @@ func (src *BgplgdSource) Neighbors() (*api.NeighborsResponse, error) {
 	// calculate the filtered routes.
 	for _, n := range nb {
 		n.RouteServerID = src.cfg.ID
-		rejectedRes, err := src.RoutesFiltered(n.ID)
+		rejectedRes, err := src.RoutesFiltered(ctx, n.ID, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -178,20 +275,22 @@ func (src *BgplgdSource) Neighbors() (*api.NeighborsResponse, error) {
 // NeighborsSummary retrievs list of neighbors, which
 // might lack details like with number of rejected routes.
 // It is much faster though.
-func (src *BgplgdSource) NeighborsSummary() (*api.NeighborsResponse, error) {
+func (src *BgplgdSource) NeighborsSummary(ctx context.Context) (*api.NeighborsResponse, error) {
 	// Query cache and see if we have a hit
 	response := src.neighborsSummaryCache.Get()
 	if response != nil {
+		metrics.CacheHit(src.cfg.ID, "neighbors_summary")
 		response.Meta.ResultFromCache = true
 		return response, nil
 	}
+	metrics.CacheMiss(src.cfg.ID, "neighbors_summary")
 
 	// Make API request and read response
-	req, err := src.ShowNeighborsRequest(context.Background())
+	req, err := src.ShowNeighborsRequest(ctx)
 	if err != nil {
 		return nil, err
 	}
-	res, err := http.DefaultClient.Do(req)
+	res, err := src.doRequest("neighbors", req)
 	if err != nil {
 		return nil, err
 	}
@@ -222,13 +321,13 @@ func (src *BgplgdSource) NeighborsSummary() (*api.NeighborsResponse, error) {
 
 // NeighborsStatus retrives the status summary
 // for all neightbors
-func (src *BgplgdSource) NeighborsStatus() (*api.NeighborsStatusResponse, error) {
+func (src *BgplgdSource) NeighborsStatus(ctx context.Context) (*api.NeighborsStatusResponse, error) {
 	// Make API request and read response
-	req, err := src.ShowNeighborsSummaryRequest(context.Background())
+	req, err := src.ShowNeighborsSummaryRequest(ctx)
 	if err != nil {
 		return nil, err
 	}
-	res, err := http.DefaultClient.Do(req)
+	res, err := src.doRequest("summary", req)
 	if err != nil {
 		return nil, err
 	}
@@ -254,41 +353,29 @@ func (src *BgplgdSource) NeighborsStatus() (*api.NeighborsStatusResponse, error)
 }
 
 // Routes retrieves the routes for a specific neighbor
-// identified by ID.
-func (src *BgplgdSource) Routes(neighborID string) (*api.RoutesResponse, error) {
-	response := src.routesCache.Get(neighborID)
-	if response != nil {
-		response.Meta.ResultFromCache = true
-		return response, nil
-	}
-
-	// Query RIB for routes received
-	req, err := src.ShowNeighborRIBRequest(context.Background(), neighborID)
-	if err != nil {
-		return nil, err
-	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+// identified by ID, optionally narrowed down by filter.
+func (src *BgplgdSource) Routes(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
+	if !filter.IsEmpty() {
+		return src.filteredRoutesResponse(ctx, neighborID, filter, true, true)
 	}
 
-	// Read and decode response
-	body, err := decoders.ReadJSONResponse(res)
-	if err != nil {
-		return nil, err
+	if response := src.routesCache.Get(neighborID); response != nil {
+		metrics.CacheHit(src.cfg.ID, "routes")
+		response.Meta.ResultFromCache = true
+		return response, nil
 	}
+	metrics.CacheMiss(src.cfg.ID, "routes")
 
-	routes, err := decodeRoutes(body)
+	received, rejected, err := src.fetchAndSplitRIB(ctx, neighborID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filtered routes are marked with a large BGP community
-	// as defined in the reject reasons.
-	received := filterReceivedRoutes(src.cfg.RejectCommunities, routes)
-	rejected := filterRejectedRoutes(src.cfg.RejectCommunities, routes)
-
-	response = &api.RoutesResponse{
+	response := &api.RoutesResponse{
 		Response: api.Response{
 			Meta: src.makeResponseMeta(),
 		},
@@ -301,40 +388,30 @@ func (src *BgplgdSource) Routes(neighborID string) (*api.RoutesResponse, error)
 	return response, nil
 }
 
-// RoutesReceived returns the routes exported by the neighbor.
-func (src *BgplgdSource) RoutesReceived(neighborID string) (*api.RoutesResponse, error) {
-	response := src.routesReceivedCache.Get(neighborID)
-	if response != nil {
-		response.Meta.ResultFromCache = true
-		return response, nil
-	}
-
-	// Query RIB for routes received
-	req, err := src.ShowNeighborRIBRequest(context.Background(), neighborID)
-	if err != nil {
-		return nil, err
-	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+// RoutesReceived returns the routes exported by the neighbor,
+// optionally narrowed down by filter.
+func (src *BgplgdSource) RoutesReceived(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
+	if !filter.IsEmpty() {
+		return src.filteredRoutesResponse(ctx, neighborID, filter, true, false)
 	}
 
-	// Read and decode response
-	body, err := decoders.ReadJSONResponse(res)
-	if err != nil {
-		return nil, err
+	if response := src.routesReceivedCache.Get(neighborID); response != nil {
+		metrics.CacheHit(src.cfg.ID, "routes_received")
+		response.Meta.ResultFromCache = true
+		return response, nil
 	}
+	metrics.CacheMiss(src.cfg.ID, "routes_received")
 
-	routes, err := decodeRoutes(body)
+	received, _, err := src.fetchAndSplitRIB(ctx, neighborID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filtered routes are marked with a large BGP community
-	// as defined in the reject reasons.
-	received := filterReceivedRoutes(src.cfg.RejectCommunities, routes)
-
-	response = &api.RoutesResponse{
+	response := &api.RoutesResponse{
 		Response: api.Response{
 			Meta: src.makeResponseMeta(),
 		},
@@ -347,40 +424,30 @@ func (src *BgplgdSource) RoutesReceived(neighborID string) (*api.RoutesResponse,
 	return response, nil
 }
 
-// RoutesFiltered retrieves the routes filtered / not valid
-func (src *BgplgdSource) RoutesFiltered(neighborID string) (*api.RoutesResponse, error) {
-	response := src.routesFilteredCache.Get(neighborID)
-	if response != nil {
-		response.Meta.ResultFromCache = true
-		return response, nil
-	}
-
-	// Query RIB for routes received
-	req, err := src.ShowNeighborRIBRequest(context.Background(), neighborID)
-	if err != nil {
-		return nil, err
-	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+// RoutesFiltered retrieves the routes filtered / not valid,
+// optionally narrowed down by filter.
+func (src *BgplgdSource) RoutesFiltered(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (*api.RoutesResponse, error) {
+	if !filter.IsEmpty() {
+		return src.filteredRoutesResponse(ctx, neighborID, filter, false, true)
 	}
 
-	// Read and decode response
-	body, err := decoders.ReadJSONResponse(res)
-	if err != nil {
-		return nil, err
+	if response := src.routesFilteredCache.Get(neighborID); response != nil {
+		metrics.CacheHit(src.cfg.ID, "routes_filtered")
+		response.Meta.ResultFromCache = true
+		return response, nil
 	}
+	metrics.CacheMiss(src.cfg.ID, "routes_filtered")
 
-	routes, err := decodeRoutes(body)
+	_, rejected, err := src.fetchAndSplitRIB(ctx, neighborID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filtered routes are marked with a large BGP community
-	// as defined in the reject reasons.
-	rejected := filterRejectedRoutes(src.cfg.RejectCommunities, routes)
-
-	response = &api.RoutesResponse{
+	response := &api.RoutesResponse{
 		Response: api.Response{
 			Meta: src.makeResponseMeta(),
 		},
@@ -393,55 +460,284 @@ func (src *BgplgdSource) RoutesFiltered(neighborID string) (*api.RoutesResponse,
 	return response, nil
 }
 
-// RoutesNotExported retrievs the routes not exported
-// from the rs for a neighbor.
-func (src *BgplgdSource) RoutesNotExported(neighborID string) (*api.RoutesResponse, error) {
+// fetchAndSplitRIB fetches the RIB for a single neighbor and splits
+// it into received and rejected routes, populating routesCache,
+// routesReceivedCache and routesFilteredCache from that one fetch.
+// The three caches are set one after another, not under a common
+// lock, so a concurrent reader can briefly see one cache updated
+// before the others. Concurrent
+// calls for the same neighborID are coalesced into a single
+// upstream request via singleflight, so that a store refresh and a
+// UI request racing each other only hit bgplgd once.
+func (src *BgplgdSource) fetchAndSplitRIB(
+	ctx context.Context,
+	neighborID string,
+) (received api.Routes, rejected api.Routes, err error) {
+	type splitRIB struct {
+		received api.Routes
+		rejected api.Routes
+	}
+
+	result, err, _ := src.ribRequests.Do(neighborID, func() (interface{}, error) {
+		routes, err := src.fetchNeighborRIB(ctx, neighborID)
+		if err != nil {
+			return nil, err
+		}
+
+		received := filterReceivedRoutes(src.cfg.RejectCommunities, routes)
+		rejected := filterRejectedRoutes(src.cfg.RejectCommunities, routes)
+
+		src.routesCache.Set(neighborID, &api.RoutesResponse{
+			Response:    api.Response{Meta: src.makeResponseMeta()},
+			Imported:    received,
+			NotExported: api.Routes{},
+			Filtered:    rejected,
+		})
+		src.routesReceivedCache.Set(neighborID, &api.RoutesResponse{
+			Response:    api.Response{Meta: src.makeResponseMeta()},
+			Imported:    received,
+			NotExported: api.Routes{},
+			Filtered:    api.Routes{},
+		})
+		src.routesFilteredCache.Set(neighborID, &api.RoutesResponse{
+			Response:    api.Response{Meta: src.makeResponseMeta()},
+			Imported:    api.Routes{},
+			NotExported: api.Routes{},
+			Filtered:    rejected,
+		})
+
+		return splitRIB{received: received, rejected: rejected}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	split := result.(splitRIB)
+	return split.received, split.rejected, nil
+}
+
+// filteredRoutesResponse services a filtered Routes/RoutesReceived/
+// RoutesFiltered request. Filtered queries are narrower than a full
+// RIB fetch and are neither cached nor coalesced, as they are
+// unlikely to be requested concurrently with the same parameters.
+func (src *BgplgdSource) filteredRoutesResponse(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+	includeReceived bool,
+	includeRejected bool,
+) (*api.RoutesResponse, error) {
+	routes, err := src.fetchNeighborRIBFiltered(ctx, neighborID, filter)
+	if err != nil {
+		return nil, err
+	}
+	routes = filterMatchingRoutes(filter, routes)
+
 	response := &api.RoutesResponse{
-		Response: api.Response{
-			Meta: src.makeResponseMeta(),
-		},
+		Response:    api.Response{Meta: src.makeResponseMeta()},
 		Imported:    api.Routes{},
 		NotExported: api.Routes{},
 		Filtered:    api.Routes{},
 	}
+	if includeReceived {
+		response.Imported = filterReceivedRoutes(src.cfg.RejectCommunities, routes)
+	}
+	if includeRejected {
+		response.Filtered = filterRejectedRoutes(src.cfg.RejectCommunities, routes)
+	}
 	return response, nil
 }
 
-// AllRoutes retrievs the entire RIB from the source. This is never
-// cached as it is processed by the store.
-func (src *BgplgdSource) AllRoutes() (*api.RoutesResponse, error) {
-	req, err := src.ShowRIBRequest(context.Background())
+// filterMatchingRoutes applies the ASN / large / extended community
+// criteria of filter to routes. bgplgd has no query parameters for
+// these, unlike Prefix/Match/AFI/Family which are already applied by
+// the upstream request, so they are enforced here instead.
+func filterMatchingRoutes(filter *api.RouteFilter, routes api.Routes) api.Routes {
+	if filter.IsEmpty() {
+		return routes
+	}
+	matching := make(api.Routes, 0, len(routes))
+	for _, route := range routes {
+		if filter.MatchesRoute(route) {
+			matching = append(matching, route)
+		}
+	}
+	return matching
+}
+
+// fetchNeighborRIB queries the unfiltered RIB for a single neighbor
+// and decodes the response.
+func (src *BgplgdSource) fetchNeighborRIB(
+	ctx context.Context,
+	neighborID string,
+) (api.Routes, error) {
+	req, err := src.ShowNeighborRIBRequest(ctx, neighborID)
 	if err != nil {
 		return nil, err
 	}
-	res, err := http.DefaultClient.Do(req)
+	res, err := src.doRequest("rib", req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := decoders.ReadJSONResponse(res)
 	if err != nil {
 		return nil, err
 	}
+	return decodeRoutes(body)
+}
 
-	// Read and decode response
+// fetchNeighborRIBFiltered queries the RIB for a single neighbor
+// narrowed down by filter and decodes the response.
+func (src *BgplgdSource) fetchNeighborRIBFiltered(
+	ctx context.Context,
+	neighborID string,
+	filter *api.RouteFilter,
+) (api.Routes, error) {
+	req, err := src.ShowNeighborRIBFilterRequest(ctx, neighborID, filter)
+	if err != nil {
+		return nil, err
+	}
+	res, err := src.doRequest("rib", req)
+	if err != nil {
+		return nil, err
+	}
 	body, err := decoders.ReadJSONResponse(res)
 	if err != nil {
 		return nil, err
 	}
+	return decodeRoutes(body)
+}
+
+// LookupPrefix searches the entire RIB for a prefix, optionally
+// narrowed down further by filter (e.g. match mode or community).
+func (src *BgplgdSource) LookupPrefix(
+	ctx context.Context,
+	prefix string,
+	filter *api.RouteFilter,
+) (*api.RoutesLookupResponse, error) {
+	if filter == nil {
+		filter = &api.RouteFilter{}
+	}
+	filter.Prefix = prefix
+
+	req, err := src.ShowRIBPrefixRequest(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	res, err := src.doRequest("rib", req)
+	if err != nil {
+		return nil, err
+	}
 
+	body, err := decoders.ReadJSONResponse(res)
+	if err != nil {
+		return nil, err
+	}
 	routes, err := decodeRoutes(body)
 	if err != nil {
 		return nil, err
 	}
+	routes = filterMatchingRoutes(filter, routes)
+
+	return &api.RoutesLookupResponse{
+		Response: api.Response{
+			Meta: src.makeResponseMeta(),
+		},
+		Routes: routes,
+	}, nil
+}
+
+// bgplgdMatchParam translates an api.MatchMode into the bgplgd
+// "match" query parameter value. It defaults to exact matching.
+func bgplgdMatchParam(mode api.MatchMode) string {
+	switch mode {
+	case api.MatchLonger:
+		return "longer"
+	case api.MatchShorter:
+		return "shorter"
+	default:
+		return "exact"
+	}
+}
 
-	// Filtered routes are marked with a large BGP community
-	// as defined in the reject reasons.
-	received := filterReceivedRoutes(src.cfg.RejectCommunities, routes)
-	rejected := filterRejectedRoutes(src.cfg.RejectCommunities, routes)
+// bgplgdFamilyParam translates an api.RouteFilter's address family
+// selector into the bgplgd "af" query parameter value. Family takes
+// precedence over the legacy AFI field when both are set, mirroring
+// gobgp's routeFamilyFromFilter. Returns "" when neither is set, in
+// which case the af parameter is omitted entirely and bgplgd searches
+// every family.
+func bgplgdFamilyParam(filter *api.RouteFilter) string {
+	if filter.Family != "" {
+		if strings.Contains(filter.Family, "ipv6") {
+			return "ipv6"
+		}
+		return "ipv4"
+	}
+	if filter.AFI == "ipv6" {
+		return "ipv6"
+	}
+	if filter.AFI == "ipv4" {
+		return "ipv4"
+	}
+	return ""
+}
 
+// RoutesNotExported retrievs the routes not exported
+// from the rs for a neighbor.
+func (src *BgplgdSource) RoutesNotExported(ctx context.Context, neighborID string) (*api.RoutesResponse, error) {
 	response := &api.RoutesResponse{
 		Response: api.Response{
 			Meta: src.makeResponseMeta(),
 		},
-		Imported:    received,
+		Imported:    api.Routes{},
 		NotExported: api.Routes{},
-		Filtered:    rejected,
+		Filtered:    api.Routes{},
 	}
 	return response, nil
 }
+
+// AllRoutes retrievs the entire RIB from the source. This is never
+// cached as it is processed by the store. Concurrent callers (e.g. a
+// store refresh racing a UI-triggered AllRoutes request) are
+// coalesced into a single upstream request via singleflight.
+func (src *BgplgdSource) AllRoutes(ctx context.Context) (*api.RoutesResponse, error) {
+	result, err, _ := src.ribRequests.Do(allRoutesSingleflightKey, func() (interface{}, error) {
+		req, err := src.ShowRIBRequest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := src.doRequest("rib", req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Read and decode response
+		body, err := decoders.ReadJSONResponse(res)
+		if err != nil {
+			return nil, err
+		}
+
+		routes, err := decodeRoutes(body)
+		if err != nil {
+			return nil, err
+		}
+
+		// Filtered routes are marked with a large BGP community
+		// as defined in the reject reasons.
+		received := filterReceivedRoutes(src.cfg.RejectCommunities, routes)
+		rejected := filterRejectedRoutes(src.cfg.RejectCommunities, routes)
+
+		return &api.RoutesResponse{
+			Response: api.Response{
+				Meta: src.makeResponseMeta(),
+			},
+			Imported:    received,
+			NotExported: api.Routes{},
+			Filtered:    rejected,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*api.RoutesResponse), nil
+}
@@ -0,0 +1,83 @@
+package openbgpd
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// TestRibRequestsCoalescing exercises the singleflight.Group
+// mechanism fetchAndSplitRIB relies on (src.ribRequests.Do, keyed by
+// neighborID) to coalesce a store refresh and a concurrent UI request
+// into a single upstream fetch.
+//
+// This does not drive fetchAndSplitRIB itself: doing so requires
+// constructing a *BgplgdSource, which in turn requires pkg/caches
+// and pkg/decoders, neither of which exists in this tree. It tests
+// singleflight.Group directly, with the same key and call shape
+// ribRequests uses; it does not detect fetchAndSplitRIB no longer
+// going through ribRequests.
+func TestRibRequestsCoalescing(t *testing.T) {
+	var ribRequests singleflight.Group
+	var calls int32
+
+	const neighborID = "neighbor-1"
+	const concurrency = 10
+
+	// The in-flight fetch is held open until every goroutine has had
+	// time to enter Do, so all of them overlap with it.
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(concurrency)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			started.Done()
+			_, _, _ = ribRequests.Do(neighborID, func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return struct{}{}, nil
+			})
+		}()
+	}
+	started.Wait()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected %d concurrent calls for the same neighborID to be coalesced into 1 upstream fetch, got %d", concurrency, got)
+	}
+}
+
+// TestRibRequestsDistinctKeys checks that distinct neighborIDs are not
+// coalesced into each other, since fetchAndSplitRIB must still fetch
+// each neighbor's RIB independently.
+func TestRibRequestsDistinctKeys(t *testing.T) {
+	var ribRequests singleflight.Group
+	var calls int32
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for _, neighborID := range []string{"neighbor-1", "neighbor-2"} {
+		neighborID := neighborID
+		go func() {
+			defer wg.Done()
+			_, _, _ = ribRequests.Do(neighborID, func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				return struct{}{}, nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected 2 distinct neighborIDs to result in 2 upstream fetches, got %d", got)
+	}
+}
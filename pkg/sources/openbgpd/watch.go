@@ -0,0 +1,78 @@
+package openbgpd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	api "github.com/alice-lg/alice-lg/pkg/api"
+)
+
+// pollInterval is how often the neighbor snapshot is refreshed to
+// synthesize events. bgplgd has no push API, so this is a tradeoff
+// between staleness and load on the target daemon.
+const pollInterval = 5 * time.Second
+
+// Subscribe emulates the GoBGP push API by polling NeighborsStatus
+// at pollInterval and diffing it against the previous snapshot,
+// synthesizing NeighborEvents for any state transitions. Route
+// events are not currently synthesized, as a full RIB diff every
+// pollInterval would defeat the purpose of this source.
+func (src *BgplgdSource) Subscribe(
+	ctx context.Context,
+) (<-chan api.NeighborEvent, <-chan api.RouteEvent, error) {
+	src.watchOnce.Do(func() {
+		go src.poll()
+	})
+
+	neighbors, routes, unsubscribe := src.events.subscribe()
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	return neighbors, routes, nil
+}
+
+// poll periodically refreshes the neighbor status snapshot and
+// publishes synthesized events for any observed state changes.
+func (src *BgplgdSource) poll() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := src.pollOnce(); err != nil {
+			log.Printf("bgplgd poll: %s", err)
+		}
+	}
+}
+
+// pollOnce fetches the current neighbor status and diffs it against
+// lastNeighborsSnapshot, publishing an event for every neighbor
+// whose state changed.
+func (src *BgplgdSource) pollOnce() error {
+	status, err := src.NeighborsStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]string, len(src.lastNeighborsSnapshot))
+	for _, n := range src.lastNeighborsSnapshot {
+		previous[n.ID] = n.State
+	}
+
+	for _, n := range status.Neighbors {
+		if previous[n.ID] == n.State {
+			continue
+		}
+		src.events.publishNeighborEvent(api.NeighborEvent{
+			RouteServerID: src.cfg.ID,
+			NeighborID:    n.ID,
+			Type:          api.NeighborEventStateChanged,
+			State:         n.State,
+		})
+		src.neighborsCache.Expire()
+		src.neighborsSummaryCache.Expire()
+	}
+
+	src.lastNeighborsSnapshot = status.Neighbors
+	return nil
+}